@@ -0,0 +1,60 @@
+package bridge
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyLoadError(t *testing.T) {
+	newResponseErr := func(statusCode int) error {
+		return &resty.ResponseError{
+			Response: &resty.Response{
+				RawResponse: &http.Response{StatusCode: statusCode},
+			},
+		}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want loadErrorKind
+	}{
+		{
+			name: "401 is auth invalid",
+			err:  newResponseErr(http.StatusUnauthorized),
+			want: loadErrorAuthInvalid,
+		},
+		{
+			name: "429 is throttled",
+			err:  newResponseErr(http.StatusTooManyRequests),
+			want: loadErrorThrottled,
+		},
+		{
+			name: "500 is transient",
+			err:  newResponseErr(http.StatusInternalServerError),
+			want: loadErrorTransient,
+		},
+		{
+			name: "wrapped response error is still classified",
+			err:  fmt.Errorf("failed to load user: %w", newResponseErr(http.StatusUnauthorized)),
+			want: loadErrorAuthInvalid,
+		},
+		{
+			name: "unrecognized error is transient",
+			err:  fmt.Errorf("some network error"),
+			want: loadErrorTransient,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyLoadError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package bridge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+// fakeAPIRateLimiter lets a test swap out the limiter newAPIThrottleHook reads, simulating what
+// SetUserLimits does to a live user.User.
+type fakeAPIRateLimiter struct {
+	limiter *rate.Limiter
+}
+
+func (f *fakeAPIRateLimiter) APILimiter() *rate.Limiter {
+	return f.limiter
+}
+
+func TestAPIThrottleHookReadsLimiterLive(t *testing.T) {
+	holder := &fakeAPIRateLimiter{limiter: rate.NewLimiter(rate.Inf, 0)}
+
+	var throttled int
+
+	hook := newAPIThrottleHook(holder, func() { throttled++ })
+
+	// With an unlimited limiter, requests pass straight through.
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, hook(context.Background(), nil))
+	}
+	assert.Equal(t, 0, throttled)
+
+	// Simulate SetUserLimits swapping the user's limiter for an exhausted one. If the hook had
+	// captured the original limiter instead of reading it fresh, this would still pass.
+	holder.limiter = rate.NewLimiter(rate.Limit(1), 1)
+	holder.limiter.Allow() // drain the single token so the next request has to wait
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := hook(ctx, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, throttled)
+}
@@ -2,7 +2,10 @@ package bridge
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"github.com/ProtonMail/gluon/imap"
 	"github.com/ProtonMail/proton-bridge/v2/internal/events"
@@ -13,8 +16,42 @@ import (
 	"github.com/sirupsen/logrus"
 	"gitlab.protontech.ch/go/liteapi"
 	"golang.org/x/exp/slices"
+	"golang.org/x/time/rate"
 )
 
+// UserState describes the current connection state of a bridge user.
+type UserState int
+
+const (
+	// UserStateLoggedOut means the user is known to the vault but is not currently authorized.
+	UserStateLoggedOut UserState = iota
+
+	// UserStateConnected means the user is authorized and its API client is reachable.
+	UserStateConnected
+
+	// UserStateDisconnected means the user was authorized but has lost contact with the API
+	// for a transient reason (network failure or server-side throttling). Its vault secrets
+	// are kept intact and IMAP continues serving its cached mail read-only while bridge
+	// retries in the background; the user is not added to the SMTP backend in this state.
+	UserStateDisconnected
+)
+
+// UserLimits configures the per-user throughput and concurrency caps enforced by the bridge.
+// A zero value for any field means that dimension is unlimited.
+type UserLimits struct {
+	// BytesPerSecondUp caps the rate at which the user's clients may upload mail (SMTP).
+	BytesPerSecondUp int
+
+	// BytesPerSecondDown caps the rate at which the user's clients may download mail (IMAP).
+	BytesPerSecondDown int
+
+	// APIRequestsPerSecond caps the rate of requests the user's clients may make to the API.
+	APIRequestsPerSecond int
+
+	// MaxIMAPSessions caps the number of concurrent IMAP sessions the user may hold open.
+	MaxIMAPSessions int
+}
+
 type UserInfo struct {
 	// UserID is the user's API ID.
 	UserID string
@@ -22,8 +59,8 @@ type UserInfo struct {
 	// Username is the user's API username.
 	Username string
 
-	// Connected is true if the user is logged in (has API auth).
-	Connected bool
+	// State is the user's current connection state.
+	State UserState
 
 	// Addresses holds the user's email addresses. The first address is the primary address.
 	Addresses []string
@@ -39,6 +76,24 @@ type UserInfo struct {
 
 	// MaxSpace is the total amount of space available to the user.
 	MaxSpace int
+
+	// CurrentAPIRate is the user's current API request rate, in requests per second.
+	CurrentAPIRate float64
+
+	// CurrentIMAPSessions is the number of IMAP sessions the user currently has open.
+	CurrentIMAPSessions int
+
+	// ThroughputSamples holds recent upload/download throughput samples, most recent last.
+	ThroughputSamples []ThroughputSample
+}
+
+// ThroughputSample is a single point-in-time measurement of a user's IMAP/SMTP throughput.
+type ThroughputSample struct {
+	// BytesPerSecondUp is the observed upload rate at the time of the sample.
+	BytesPerSecondUp int
+
+	// BytesPerSecondDown is the observed download rate at the time of the sample.
+	BytesPerSecondDown int
 }
 
 // GetUserIDs returns the IDs of all known users (authorized or not).
@@ -55,7 +110,15 @@ func (bridge *Bridge) GetUserInfo(userID string) (UserInfo, error) {
 
 	user, ok := bridge.users[userID]
 	if !ok {
-		return getUserInfo(vaultUser.UserID(), vaultUser.Username(), vaultUser.AddressMode()), nil
+		// A disconnected user never makes it into bridge.users (addUser never ran for it), so
+		// it must be distinguished from a merely logged-out one here, not below.
+		state := UserStateLoggedOut
+
+		if bridge.isDisconnected(userID) {
+			state = UserStateDisconnected
+		}
+
+		return getUserInfo(vaultUser.UserID(), vaultUser.Username(), vaultUser.AddressMode(), state), nil
 	}
 
 	return getConnUserInfo(user), nil
@@ -69,16 +132,61 @@ func (bridge *Bridge) QueryUserInfo(query string) (UserInfo, error) {
 		}
 	}
 
+	// Users that are logged out or disconnected aren't in bridge.users, and bridge only has
+	// their vault record to go on, which doesn't track addresses — so they can only be found by
+	// username here.
+	for _, userID := range bridge.vault.GetUserIDs() {
+		if _, ok := bridge.users[userID]; ok {
+			continue
+		}
+
+		vaultUser, err := bridge.vault.GetUser(userID)
+		if err != nil {
+			continue
+		}
+
+		if vaultUser.Username() == query {
+			return bridge.GetUserInfo(userID)
+		}
+	}
+
 	return UserInfo{}, ErrNoSuchUser
 }
 
+// TwoFAChallenge describes the second-factor methods enabled on the account being authorized,
+// as reported by the API during login.
+type TwoFAChallenge struct {
+	// TOTP is true if the account has a TOTP device enrolled.
+	TOTP bool
+
+	// FIDO2 is true if the account has one or more WebAuthn/FIDO2 security keys enrolled.
+	FIDO2 bool
+
+	// FIDO2Request holds the server-issued WebAuthn assertion request to be signed by the
+	// chosen security key. It is only valid if FIDO2 is true.
+	FIDO2Request liteapi.FIDO2AssertionRequest
+}
+
+// TwoFAResponse carries the second factor supplied by the user in response to a TwoFAChallenge.
+// Exactly one of TOTP, FIDO2 or RecoveryCode should be set, matching the method the user chose.
+type TwoFAResponse struct {
+	// TOTP is a time-based one-time password code.
+	TOTP string
+
+	// FIDO2 is a signed WebAuthn assertion.
+	FIDO2 *liteapi.FIDO2AssertionResponse
+
+	// RecoveryCode is a one-time account recovery code.
+	RecoveryCode string
+}
+
 // LoginUser authorizes a new bridge user with the given username and password.
-// If necessary, a TOTP and mailbox password are requested via the callbacks.
+// If necessary, a second factor and mailbox password are requested via the callbacks.
 func (bridge *Bridge) LoginUser(
 	ctx context.Context,
 	username string,
 	password []byte,
-	getTOTP func() (string, error),
+	get2FA func(challenge TwoFAChallenge) (TwoFAResponse, error),
 	getKeyPass func() ([]byte, error),
 ) (string, error) {
 	client, auth, err := bridge.api.NewClientWithLogin(ctx, username, password)
@@ -92,13 +200,8 @@ func (bridge *Bridge) LoginUser(
 				return "", ErrUserAlreadyLoggedIn
 			}
 
-			if auth.TwoFA.Enabled == liteapi.TOTPEnabled {
-				totp, err := getTOTP()
-				if err != nil {
-					return "", fmt.Errorf("failed to get TOTP: %w", err)
-				}
-
-				if err := client.Auth2FA(ctx, liteapi.Auth2FAReq{TwoFactorCode: totp}); err != nil {
+			if auth.TwoFA.Enabled != liteapi.TwoFADisabled {
+				if err := bridge.auth2FA(ctx, client, username, auth, get2FA); err != nil {
 					return "", fmt.Errorf("failed to authorize 2FA: %w", err)
 				}
 			}
@@ -137,6 +240,113 @@ func (bridge *Bridge) LoginUser(
 	return userID, nil
 }
 
+// twoFAAuthClient is the subset of *liteapi.Client that dispatchTwoFA needs, factored out as an
+// interface so tests can exercise the dispatch logic against a fake.
+type twoFAAuthClient interface {
+	Auth2FA(ctx context.Context, req liteapi.Auth2FAReq) error
+	Auth2FAFIDO2(ctx context.Context, req liteapi.Auth2FAFIDO2Req) error
+	Auth2FARecoveryCode(ctx context.Context, req liteapi.Auth2FARecoveryCodeReq) error
+}
+
+// auth2FA prompts for a second factor matching the methods enabled on the account and submits
+// it to whichever liteapi endpoint matches the response. If the server rejects the attempt, a
+// events.UserTwoFactorFailed event is published so the frontend can re-prompt.
+func (bridge *Bridge) auth2FA(
+	ctx context.Context,
+	client *liteapi.Client,
+	username string,
+	auth liteapi.Auth,
+	get2FA func(challenge TwoFAChallenge) (TwoFAResponse, error),
+) error {
+	challenge := TwoFAChallenge{
+		TOTP:  auth.TwoFA.Enabled&liteapi.TOTPEnabled != 0,
+		FIDO2: auth.TwoFA.Enabled&liteapi.FIDO2Enabled != 0,
+	}
+
+	if challenge.FIDO2 {
+		challenge.FIDO2Request = auth.TwoFA.FIDO2.AuthenticationOptions
+	}
+
+	resp, err := get2FA(challenge)
+	if err != nil {
+		return fmt.Errorf("failed to get 2FA response: %w", err)
+	}
+
+	if err := validate2FAResponse(challenge, resp); err != nil {
+		bridge.publish(events.UserTwoFactorFailed{
+			Username: username,
+		})
+
+		return err
+	}
+
+	if err := dispatchTwoFA(ctx, client, resp); err != nil {
+		bridge.publish(events.UserTwoFactorFailed{
+			Username: username,
+		})
+
+		return err
+	}
+
+	return nil
+}
+
+// dispatchTwoFA submits resp to whichever liteapi endpoint matches the method it selects. resp
+// is assumed to have already been validated against the challenge that produced it.
+func dispatchTwoFA(ctx context.Context, client twoFAAuthClient, resp TwoFAResponse) error {
+	switch {
+	case resp.FIDO2 != nil:
+		return client.Auth2FAFIDO2(ctx, liteapi.Auth2FAFIDO2Req{AssertionResponse: *resp.FIDO2})
+
+	case resp.RecoveryCode != "":
+		return client.Auth2FARecoveryCode(ctx, liteapi.Auth2FARecoveryCodeReq{RecoveryCode: resp.RecoveryCode})
+
+	default:
+		return client.Auth2FA(ctx, liteapi.Auth2FAReq{TwoFactorCode: resp.TOTP})
+	}
+}
+
+// ErrInvalid2FAResponse is returned when a TwoFAResponse doesn't select exactly one second
+// factor, or selects one that wasn't actually enabled on the account per its TwoFAChallenge.
+var ErrInvalid2FAResponse = errors.New("two-factor response did not match an enabled method")
+
+// validate2FAResponse checks resp against the challenge that prompted it, so a caller bug (e.g.
+// a zero-value response, or choosing FIDO2 on an account that only has TOTP enabled) is rejected
+// locally instead of being sent on to the API as an empty or mismatched TOTP code.
+func validate2FAResponse(challenge TwoFAChallenge, resp TwoFAResponse) error {
+	var selected int
+
+	for _, set := range []bool{resp.TOTP != "", resp.FIDO2 != nil, resp.RecoveryCode != ""} {
+		if set {
+			selected++
+		}
+	}
+
+	if selected > 1 {
+		return fmt.Errorf("%w: more than one second factor was supplied", ErrInvalid2FAResponse)
+	}
+
+	switch {
+	case resp.FIDO2 != nil:
+		if !challenge.FIDO2 {
+			return fmt.Errorf("%w: FIDO2 is not enabled on this account", ErrInvalid2FAResponse)
+		}
+
+	case resp.RecoveryCode != "":
+		// Recovery codes are accepted regardless of which other methods are enabled.
+
+	case resp.TOTP != "":
+		if !challenge.TOTP {
+			return fmt.Errorf("%w: TOTP is not enabled on this account", ErrInvalid2FAResponse)
+		}
+
+	default:
+		return fmt.Errorf("%w: no second factor was supplied", ErrInvalid2FAResponse)
+	}
+
+	return nil
+}
+
 // LogoutUser logs out the given user.
 func (bridge *Bridge) LogoutUser(ctx context.Context, userID string) error {
 	if err := bridge.logoutUser(ctx, userID); err != nil {
@@ -194,6 +404,41 @@ func (bridge *Bridge) SetAddressMode(ctx context.Context, userID string, mode va
 	return nil
 }
 
+// SetUserLimits sets the throughput/concurrency limits for the given user and persists them in
+// the vault. The API throttle hook fetches the user's API limiter on every request, so new API
+// limits apply from the next request; the IMAP and SMTP connectors are handed the user's
+// download and upload limiters once, when addUser creates them.
+func (bridge *Bridge) SetUserLimits(userID string, limits UserLimits) error {
+	vaultUser, err := bridge.vault.GetUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := vaultUser.SetUserLimits(limits); err != nil {
+		return fmt.Errorf("failed to set user limits: %w", err)
+	}
+
+	user, ok := bridge.users[userID]
+	if !ok {
+		return nil
+	}
+
+	user.SetLimiters(limits)
+
+	return nil
+}
+
+// getUserLimits returns the persisted limits for the given user, or the zero value (unlimited)
+// if the user has none configured or cannot be found in the vault.
+func (bridge *Bridge) getUserLimits(userID string) UserLimits {
+	vaultUser, err := bridge.vault.GetUser(userID)
+	if err != nil {
+		return UserLimits{}
+	}
+
+	return vaultUser.UserLimits()
+}
+
 func (bridge *Bridge) loginUser(ctx context.Context, client *liteapi.Client, authUID, authRef string, keyPass []byte) (string, error) {
 	apiUser, err := client.GetUser(ctx)
 	if err != nil {
@@ -229,14 +474,28 @@ func (bridge *Bridge) loadUsers() error {
 		}
 
 		if err := bridge.loadUser(user); err != nil {
-			if _, ok := err.(*resty.ResponseError); ok {
-				logrus.WithError(err).Error("Failed to load connected user, clearing its secrets from vault")
+			if classifyLoadError(err) == loadErrorAuthInvalid {
+				logrus.WithError(err).Error("Failed to load connected user: auth invalidated, clearing its secrets from vault")
 
 				if err := user.Clear(); err != nil {
 					logrus.WithError(err).Error("Failed to clear user")
 				}
+
+				bridge.publish(events.UserDeauthenticated{
+					UserID: user.UserID(),
+				})
 			} else {
-				logrus.WithError(err).Error("Failed to load connected user")
+				logrus.WithError(err).Error("Failed to load connected user, entering disconnected state")
+
+				stop := bridge.markDisconnected(user.UserID())
+
+				bridge.addCachedIMAPUser(context.Background(), user)
+
+				bridge.publish(events.UserConnectionLost{
+					UserID: user.UserID(),
+				})
+
+				go bridge.reconnectLoop(user, stop)
 			}
 
 			return nil
@@ -250,6 +509,157 @@ func (bridge *Bridge) loadUsers() error {
 	})
 }
 
+// loadErrorKind classifies why loadUser failed, so loadUsers knows whether it is safe to clear
+// the user's vault secrets or whether the failure might resolve itself on retry.
+type loadErrorKind int
+
+const (
+	// loadErrorTransient covers network failures, TLS-pinning rejections, and any other error
+	// we can't positively attribute to the API rejecting the user's credentials. We default to
+	// this so an error we don't recognize never costs the user their session.
+	loadErrorTransient loadErrorKind = iota
+
+	// loadErrorAuthInvalid means the API rejected the refresh token itself (401), so the user
+	// must fully re-login.
+	loadErrorAuthInvalid
+
+	// loadErrorThrottled means the API rejected the request because the client is being
+	// server-side rate-limited; retrying after a backoff should succeed.
+	loadErrorThrottled
+)
+
+// classifyLoadError inspects the error returned by loadUser and decides whether it indicates
+// that the user's authentication has been invalidated, or whether it is a transient condition
+// (network failure or server-side throttling) that should not cost the user their session.
+func classifyLoadError(err error) loadErrorKind {
+	var responseErr *resty.ResponseError
+
+	if !errors.As(err, &responseErr) {
+		return loadErrorTransient
+	}
+
+	switch responseErr.Response.StatusCode() {
+	case http.StatusUnauthorized:
+		return loadErrorAuthInvalid
+
+	case http.StatusTooManyRequests:
+		return loadErrorThrottled
+
+	default:
+		return loadErrorTransient
+	}
+}
+
+// markDisconnected records the given user as disconnected (authorized but unreachable) so that
+// GetUserInfo reports UserStateDisconnected, and returns a channel that reconnectLoop should
+// select on so it can be woken up early instead of waiting out its current backoff.
+func (bridge *Bridge) markDisconnected(userID string) <-chan struct{} {
+	bridge.disconnectedLock.Lock()
+	defer bridge.disconnectedLock.Unlock()
+
+	stop := make(chan struct{})
+
+	bridge.disconnected[userID] = stop
+
+	return stop
+}
+
+// clearDisconnected removes the given user's disconnected marker, if any, and closes its stop
+// channel so a running reconnectLoop for it returns immediately. It is safe to call for a user
+// that isn't currently marked as disconnected.
+func (bridge *Bridge) clearDisconnected(userID string) {
+	bridge.disconnectedLock.Lock()
+	defer bridge.disconnectedLock.Unlock()
+
+	if stop, ok := bridge.disconnected[userID]; ok {
+		close(stop)
+		delete(bridge.disconnected, userID)
+	}
+}
+
+// isDisconnected reports whether the given user is currently marked as disconnected.
+func (bridge *Bridge) isDisconnected(userID string) bool {
+	bridge.disconnectedLock.Lock()
+	defer bridge.disconnectedLock.Unlock()
+
+	_, ok := bridge.disconnected[userID]
+
+	return ok
+}
+
+// addCachedIMAPUser loads the user's already-persisted mailboxes into gluon in read-only mode,
+// using only what is already cached on disk. It is a no-op if the user has never been loaded
+// into gluon before (so there is nothing cached to serve). This lets IMAP keep serving a
+// disconnected user's existing mail while bridge cannot reach the API to authenticate it fully.
+func (bridge *Bridge) addCachedIMAPUser(ctx context.Context, vaultUser *vault.User) {
+	gluonIDs := vaultUser.GluonIDs()
+	if len(gluonIDs) == 0 {
+		return
+	}
+
+	conn := user.NewReadOnlyIMAPConnector(vaultUser)
+
+	for _, gluonID := range gluonIDs {
+		if err := bridge.imapServer.LoadUser(ctx, conn, gluonID, vaultUser.GluonKey()); err != nil {
+			logrus.WithError(err).Error("Failed to load cached IMAP user in read-only mode")
+		}
+	}
+}
+
+// reconnectLoop retries loading a user that failed to load for a transient reason, backing off
+// exponentially between attempts. IMAP continues serving the user's cached mail read-only from
+// the existing gluon store while this runs (see addCachedIMAPUser); SMTP remains unavailable for
+// the user since sending requires a live API connection. It stops once the user reconnects, its
+// auth is found to be invalid, or stop is closed (e.g. because the user logged out or was
+// deleted while still disconnected).
+func (bridge *Bridge) reconnectLoop(vaultUser *vault.User, stop <-chan struct{}) {
+	const (
+		minBackoff = 10 * time.Second
+		maxBackoff = 5 * time.Minute
+	)
+
+	userID := vaultUser.UserID()
+
+	backoff := minBackoff
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(backoff):
+		}
+
+		err := bridge.loadUser(vaultUser)
+		if err == nil {
+			bridge.clearDisconnected(userID)
+
+			bridge.publish(events.UserLoaded{UserID: userID})
+
+			return
+		}
+
+		if classifyLoadError(err) == loadErrorAuthInvalid {
+			logrus.WithError(err).Error("Failed to reconnect user: auth invalidated, clearing its secrets from vault")
+
+			bridge.clearDisconnected(userID)
+
+			if err := vaultUser.Clear(); err != nil {
+				logrus.WithError(err).Error("Failed to clear user")
+			}
+
+			bridge.publish(events.UserDeauthenticated{UserID: userID})
+
+			return
+		}
+
+		logrus.WithError(err).Warn("Failed to reconnect user, will retry")
+
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
 // loadUser loads an existing user from the vault.
 func (bridge *Bridge) loadUser(user *vault.User) error {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -308,13 +718,15 @@ func (bridge *Bridge) addUser(
 		user = newUser
 	}
 
-	// Connect the user's address(es) to gluon.
+	user.SetLimiters(bridge.getUserLimits(apiUser.ID))
+
+	// Connect the user's address(es) to gluon, throttling downloaded bytes against the user's limits.
 	if err := bridge.addIMAPUser(ctx, user); err != nil {
 		return fmt.Errorf("failed to add IMAP user: %w", err)
 	}
 
-	// Connect the user's address(es) to the SMTP server.
-	if err := bridge.smtpBackend.addUser(user); err != nil {
+	// Connect the user's address(es) to the SMTP server, throttling uploaded bytes against the user's limits.
+	if err := bridge.smtpBackend.addUser(user, user.UploadLimiter()); err != nil {
 		return fmt.Errorf("failed to add user to SMTP backend: %w", err)
 	}
 
@@ -343,9 +755,41 @@ func (bridge *Bridge) addUser(
 		return nil
 	})
 
+	// Throttle outgoing API requests to the user's configured rate, publishing events.UserThrottled
+	// when a request has to wait for the bucket to refill.
+	client.AddPreRequestHook(newAPIThrottleHook(user, func() {
+		bridge.publish(events.UserThrottled{
+			UserID: apiUser.ID,
+		})
+	}))
+
 	return nil
 }
 
+// apiRateLimiter is the subset of *user.User that newAPIThrottleHook needs, carved out so the
+// hook can be tested without a real user.User.
+type apiRateLimiter interface {
+	APILimiter() *rate.Limiter
+}
+
+// newAPIThrottleHook returns a pre-request hook that enforces limiter's current API rate limit,
+// calling onThrottled whenever a request has to wait for the bucket to refill. The limiter is
+// fetched from limiter on every call (rather than captured once) so that a later SetUserLimits
+// takes effect immediately, instead of being masked by a stale limiter held in this closure.
+func newAPIThrottleHook(limiter apiRateLimiter, onThrottled func()) func(ctx context.Context, req *resty.Request) error {
+	return func(ctx context.Context, req *resty.Request) error {
+		l := limiter.APILimiter()
+
+		if l.Allow() {
+			return nil
+		}
+
+		onThrottled()
+
+		return l.Wait(ctx)
+	}
+}
+
 func (bridge *Bridge) addNewUser(
 	ctx context.Context,
 	client *liteapi.Client,
@@ -400,7 +844,7 @@ func (bridge *Bridge) addExistingUser(
 
 // addIMAPUser connects the given user to gluon.
 func (bridge *Bridge) addIMAPUser(ctx context.Context, user *user.User) error {
-	imapConn, err := user.NewIMAPConnectors()
+	imapConn, err := user.NewIMAPConnectors(user.DownloadLimiter())
 	if err != nil {
 		return fmt.Errorf("failed to create IMAP connectors: %w", err)
 	}
@@ -427,6 +871,10 @@ func (bridge *Bridge) addIMAPUser(ctx context.Context, user *user.User) error {
 
 // logoutUser logs the given user out from bridge.
 func (bridge *Bridge) logoutUser(ctx context.Context, userID string) error {
+	// Stop any reconnectLoop retrying this user in the background; logging out makes those
+	// retries moot and, left running, they would otherwise keep retrying indefinitely.
+	bridge.clearDisconnected(userID)
+
 	user, ok := bridge.users[userID]
 	if !ok {
 		return ErrNoSuchUser
@@ -457,6 +905,10 @@ func (bridge *Bridge) logoutUser(ctx context.Context, userID string) error {
 
 // deleteUser deletes the given user from bridge.
 func (bridge *Bridge) deleteUser(ctx context.Context, userID string) {
+	// Stop any reconnectLoop retrying this user before its vault entry disappears out from
+	// under it.
+	bridge.clearDisconnected(userID)
+
 	if user, ok := bridge.users[userID]; ok {
 		if err := bridge.smtpBackend.removeUser(user); err != nil {
 			logrus.WithError(err).Error("Failed to remove user from SMTP backend")
@@ -484,25 +936,30 @@ func (bridge *Bridge) deleteUser(ctx context.Context, userID string) {
 	delete(bridge.users, userID)
 }
 
-// getUserInfo returns information about a disconnected user.
-func getUserInfo(userID, username string, addressMode vault.AddressMode) UserInfo {
+// getUserInfo returns information about a user that isn't in bridge.users, i.e. one that is
+// logged out or disconnected.
+func getUserInfo(userID, username string, addressMode vault.AddressMode, state UserState) UserInfo {
 	return UserInfo{
+		State:       state,
 		UserID:      userID,
 		Username:    username,
 		AddressMode: addressMode,
 	}
 }
 
-// getConnUserInfo returns information about a connected user.
+// getConnUserInfo returns information about a user present in bridge.users, i.e. a connected one.
 func getConnUserInfo(user *user.User) UserInfo {
 	return UserInfo{
-		Connected:   true,
-		UserID:      user.ID(),
-		Username:    user.Name(),
-		Addresses:   user.Emails(),
-		AddressMode: user.GetAddressMode(),
-		BridgePass:  user.BridgePass(),
-		UsedSpace:   user.UsedSpace(),
-		MaxSpace:    user.MaxSpace(),
-	}
-}
\ No newline at end of file
+		State:               UserStateConnected,
+		UserID:              user.ID(),
+		Username:            user.Name(),
+		Addresses:           user.Emails(),
+		AddressMode:         user.GetAddressMode(),
+		BridgePass:          user.BridgePass(),
+		UsedSpace:           user.UsedSpace(),
+		MaxSpace:            user.MaxSpace(),
+		CurrentAPIRate:      user.CurrentAPIRate(),
+		CurrentIMAPSessions: user.CurrentIMAPSessions(),
+		ThroughputSamples:   user.ThroughputSamples(),
+	}
+}
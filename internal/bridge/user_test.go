@@ -0,0 +1,131 @@
+package bridge
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.protontech.ch/go/liteapi"
+)
+
+// fakeTwoFAClient is a fake liteapi auth client that records which endpoint was called and
+// returns a canned error, so dispatchTwoFA's routing can be tested without a real API.
+type fakeTwoFAClient struct {
+	err error
+
+	totpReq     *liteapi.Auth2FAReq
+	fido2Req    *liteapi.Auth2FAFIDO2Req
+	recoveryReq *liteapi.Auth2FARecoveryCodeReq
+}
+
+func (f *fakeTwoFAClient) Auth2FA(_ context.Context, req liteapi.Auth2FAReq) error {
+	f.totpReq = &req
+	return f.err
+}
+
+func (f *fakeTwoFAClient) Auth2FAFIDO2(_ context.Context, req liteapi.Auth2FAFIDO2Req) error {
+	f.fido2Req = &req
+	return f.err
+}
+
+func (f *fakeTwoFAClient) Auth2FARecoveryCode(_ context.Context, req liteapi.Auth2FARecoveryCodeReq) error {
+	f.recoveryReq = &req
+	return f.err
+}
+
+func TestDispatchTwoFA(t *testing.T) {
+	t.Run("TOTP goes to Auth2FA", func(t *testing.T) {
+		client := &fakeTwoFAClient{}
+
+		assert.NoError(t, dispatchTwoFA(context.Background(), client, TwoFAResponse{TOTP: "123456"}))
+		assert.Equal(t, "123456", client.totpReq.TwoFactorCode)
+		assert.Nil(t, client.fido2Req)
+		assert.Nil(t, client.recoveryReq)
+	})
+
+	t.Run("FIDO2 goes to Auth2FAFIDO2", func(t *testing.T) {
+		client := &fakeTwoFAClient{}
+		assertion := &liteapi.FIDO2AssertionResponse{}
+
+		assert.NoError(t, dispatchTwoFA(context.Background(), client, TwoFAResponse{FIDO2: assertion}))
+		assert.Equal(t, *assertion, client.fido2Req.AssertionResponse)
+		assert.Nil(t, client.totpReq)
+		assert.Nil(t, client.recoveryReq)
+	})
+
+	t.Run("recovery code goes to Auth2FARecoveryCode", func(t *testing.T) {
+		client := &fakeTwoFAClient{}
+
+		assert.NoError(t, dispatchTwoFA(context.Background(), client, TwoFAResponse{RecoveryCode: "abcd-1234"}))
+		assert.Equal(t, "abcd-1234", client.recoveryReq.RecoveryCode)
+		assert.Nil(t, client.totpReq)
+		assert.Nil(t, client.fido2Req)
+	})
+
+	t.Run("API rejection is propagated", func(t *testing.T) {
+		client := &fakeTwoFAClient{err: errors.New("invalid code")}
+
+		err := dispatchTwoFA(context.Background(), client, TwoFAResponse{TOTP: "000000"})
+		assert.ErrorIs(t, err, client.err)
+	})
+}
+
+func TestValidate2FAResponse(t *testing.T) {
+	fido2Resp := &liteapi.FIDO2AssertionResponse{}
+
+	tests := []struct {
+		name      string
+		challenge TwoFAChallenge
+		resp      TwoFAResponse
+		wantErr   bool
+	}{
+		{
+			name:      "TOTP accepted when enabled",
+			challenge: TwoFAChallenge{TOTP: true},
+			resp:      TwoFAResponse{TOTP: "123456"},
+		},
+		{
+			name:      "TOTP rejected when not enabled",
+			challenge: TwoFAChallenge{FIDO2: true},
+			resp:      TwoFAResponse{TOTP: "123456"},
+			wantErr:   true,
+		},
+		{
+			name:      "FIDO2 accepted when enabled",
+			challenge: TwoFAChallenge{FIDO2: true},
+			resp:      TwoFAResponse{FIDO2: fido2Resp},
+		},
+		{
+			name:      "FIDO2 rejected when not enabled",
+			challenge: TwoFAChallenge{TOTP: true},
+			resp:      TwoFAResponse{FIDO2: fido2Resp},
+			wantErr:   true,
+		},
+		{
+			name:      "recovery code accepted regardless of enabled methods",
+			challenge: TwoFAChallenge{TOTP: true},
+			resp:      TwoFAResponse{RecoveryCode: "abcd-1234"},
+		},
+		{
+			name:      "empty response rejected",
+			challenge: TwoFAChallenge{TOTP: true},
+			resp:      TwoFAResponse{},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			err := validate2FAResponse(tt.challenge, tt.resp)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalid2FAResponse)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}